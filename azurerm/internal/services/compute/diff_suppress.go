@@ -0,0 +1,15 @@
+package compute
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// ignoreCaseDiffSuppressFunc suppresses the diff between two strings which differ only by case - the
+// Azure Compute APIs accept enum values such as `caching` and `storage_account_type` case-insensitively,
+// but echo back whatever casing was supplied (or their own canonical casing), which otherwise leaves a
+// perpetual diff for users (or other tooling) that don't match that casing exactly.
+func ignoreCaseDiffSuppressFunc(_, old, new string, _ *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
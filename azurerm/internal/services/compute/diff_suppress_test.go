@@ -0,0 +1,24 @@
+package compute
+
+import "testing"
+
+func TestIgnoreCaseDiffSuppressFunc(t *testing.T) {
+	testCases := []struct {
+		old      string
+		new      string
+		expected bool
+	}{
+		{"ReadWrite", "ReadWrite", true},
+		{"ReadWrite", "readwrite", true},
+		{"Premium_LRS", "premium_lrs", true},
+		{"ReadWrite", "ReadOnly", false},
+		{"", "ReadWrite", false},
+	}
+
+	for _, tc := range testCases {
+		actual := ignoreCaseDiffSuppressFunc("", tc.old, tc.new, nil)
+		if actual != tc.expected {
+			t.Fatalf("expected ignoreCaseDiffSuppressFunc(%q, %q) to return %t but got %t", tc.old, tc.new, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package compute
+
+import (
+	"fmt"
+
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+)
+
+type ManagedDiskID struct {
+	ResourceGroup string
+	Name          string
+}
+
+func ParseManagedDiskID(input string) (*ManagedDiskID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Managed Disk ID %q: %+v", input, err)
+	}
+
+	disk := ManagedDiskID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if disk.Name, err = id.PopSegment("disks"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &disk, nil
+}
+
+func ValidateManagedDiskID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseManagedDiskID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
+
+type SnapshotID struct {
+	ResourceGroup string
+	Name          string
+}
+
+func ParseSnapshotID(input string) (*SnapshotID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Snapshot ID %q: %+v", input, err)
+	}
+
+	snapshot := SnapshotID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if snapshot.Name, err = id.PopSegment("snapshots"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+func ValidateSnapshotID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseSnapshotID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
+
+type ImageID struct {
+	ResourceGroup string
+	Name          string
+}
+
+func ParseImageID(input string) (*ImageID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Image ID %q: %+v", input, err)
+	}
+
+	image := ImageID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if image.Name, err = id.PopSegment("images"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+func ValidateImageID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseImageID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
+
+type DiskEncryptionSetID struct {
+	ResourceGroup string
+	Name          string
+}
+
+func ParseDiskEncryptionSetID(input string) (*DiskEncryptionSetID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Disk Encryption Set ID %q: %+v", input, err)
+	}
+
+	desID := DiskEncryptionSetID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if desID.Name, err = id.PopSegment("diskEncryptionSets"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &desID, nil
+}
+
+func ValidateDiskEncryptionSetID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseDiskEncryptionSetID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
+
+type AvailabilitySetID struct {
+	ResourceGroup string
+	Name          string
+}
+
+func ParseAvailabilitySetID(input string) (*AvailabilitySetID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Availability Set ID %q: %+v", input, err)
+	}
+
+	availabilitySet := AvailabilitySetID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if availabilitySet.Name, err = id.PopSegment("availabilitySets"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &availabilitySet, nil
+}
+
+func ValidateAvailabilitySetID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseAvailabilitySetID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
+
+type ProximityPlacementGroupID struct {
+	ResourceGroup string
+	Name          string
+}
+
+func ParseProximityPlacementGroupID(input string) (*ProximityPlacementGroupID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Proximity Placement Group ID %q: %+v", input, err)
+	}
+
+	ppg := ProximityPlacementGroupID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if ppg.Name, err = id.PopSegment("proximityPlacementGroups"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &ppg, nil
+}
+
+func ValidateProximityPlacementGroupID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseProximityPlacementGroupID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
+
+type VirtualMachineScaleSetID struct {
+	ResourceGroup string
+	Name          string
+}
+
+func ParseVirtualMachineScaleSetID(input string) (*VirtualMachineScaleSetID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Virtual Machine Scale Set ID %q: %+v", input, err)
+	}
+
+	vmss := VirtualMachineScaleSetID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if vmss.Name, err = id.PopSegment("virtualMachineScaleSets"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &vmss, nil
+}
+
+func ValidateVirtualMachineScaleSetID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseVirtualMachineScaleSetID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
+
+type DedicatedHostID struct {
+	ResourceGroup string
+	HostGroupName string
+	HostName      string
+}
+
+func ParseDedicatedHostID(input string) (*DedicatedHostID, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Dedicated Host ID %q: %+v", input, err)
+	}
+
+	host := DedicatedHostID{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if host.HostGroupName, err = id.PopSegment("hostGroups"); err != nil {
+		return nil, err
+	}
+
+	if host.HostName, err = id.PopSegment("hosts"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &host, nil
+}
+
+func ValidateDedicatedHostID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if _, err := ParseDedicatedHostID(v); err != nil {
+		errors = append(errors, fmt.Errorf("Can not parse %q as a resource id: %v", k, err))
+		return
+	}
+
+	return warnings, errors
+}
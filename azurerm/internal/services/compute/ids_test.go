@@ -0,0 +1,437 @@
+package compute
+
+import "testing"
+
+func TestParseManagedDiskID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *ManagedDiskID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000",
+			Expected: nil,
+		},
+		{
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/Disks/disk1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/disks/disk1",
+			Expected: &ManagedDiskID{
+				ResourceGroup: "mygroup1",
+				Name:          "disk1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/disks/disk1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseManagedDiskID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+
+			if actual.ResourceGroup != tc.Expected.ResourceGroup {
+				t.Fatalf("expected ResourceGroup %q but got %q", tc.Expected.ResourceGroup, actual.ResourceGroup)
+			}
+			if actual.Name != tc.Expected.Name {
+				t.Fatalf("expected Name %q but got %q", tc.Expected.Name, actual.Name)
+			}
+		})
+	}
+}
+
+func TestParseSnapshotID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *SnapshotID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			// missing subscription
+			Input:    "/resourceGroups/mygroup1/providers/Microsoft.Compute/snapshots/snap1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/Snapshots/snap1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/snapshots/snap1",
+			Expected: &SnapshotID{
+				ResourceGroup: "mygroup1",
+				Name:          "snap1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/snapshots/snap1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseSnapshotID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+			if actual.ResourceGroup != tc.Expected.ResourceGroup || actual.Name != tc.Expected.Name {
+				t.Fatalf("expected %+v but got %+v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseImageID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *ImageID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			// missing subscription
+			Input:    "/resourceGroups/mygroup1/providers/Microsoft.Compute/images/image1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/Images/image1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/images/image1",
+			Expected: &ImageID{
+				ResourceGroup: "mygroup1",
+				Name:          "image1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/images/image1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseImageID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+			if actual.ResourceGroup != tc.Expected.ResourceGroup || actual.Name != tc.Expected.Name {
+				t.Fatalf("expected %+v but got %+v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseDiskEncryptionSetID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *DiskEncryptionSetID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			// missing subscription
+			Input:    "/resourceGroups/mygroup1/providers/Microsoft.Compute/diskEncryptionSets/des1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/DiskEncryptionSets/des1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/diskEncryptionSets/des1",
+			Expected: &DiskEncryptionSetID{
+				ResourceGroup: "mygroup1",
+				Name:          "des1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/diskEncryptionSets/des1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseDiskEncryptionSetID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+			if actual.ResourceGroup != tc.Expected.ResourceGroup || actual.Name != tc.Expected.Name {
+				t.Fatalf("expected %+v but got %+v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseAvailabilitySetID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *AvailabilitySetID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			// missing subscription
+			Input:    "/resourceGroups/mygroup1/providers/Microsoft.Compute/availabilitySets/set1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/AvailabilitySets/set1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/availabilitySets/set1",
+			Expected: &AvailabilitySetID{
+				ResourceGroup: "mygroup1",
+				Name:          "set1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/availabilitySets/set1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseAvailabilitySetID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+			if actual.ResourceGroup != tc.Expected.ResourceGroup || actual.Name != tc.Expected.Name {
+				t.Fatalf("expected %+v but got %+v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseProximityPlacementGroupID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *ProximityPlacementGroupID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			// missing subscription
+			Input:    "/resourceGroups/mygroup1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/ProximityPlacementGroups/ppg1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			Expected: &ProximityPlacementGroupID{
+				ResourceGroup: "mygroup1",
+				Name:          "ppg1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseProximityPlacementGroupID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+			if actual.ResourceGroup != tc.Expected.ResourceGroup || actual.Name != tc.Expected.Name {
+				t.Fatalf("expected %+v but got %+v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseVirtualMachineScaleSetID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *VirtualMachineScaleSetID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			// missing subscription
+			Input:    "/resourceGroups/mygroup1/providers/Microsoft.Compute/virtualMachineScaleSets/vmss1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/virtualmachinescalesets/vmss1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/virtualMachineScaleSets/vmss1",
+			Expected: &VirtualMachineScaleSetID{
+				ResourceGroup: "mygroup1",
+				Name:          "vmss1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/virtualMachineScaleSets/vmss1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseVirtualMachineScaleSetID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+			if actual.ResourceGroup != tc.Expected.ResourceGroup || actual.Name != tc.Expected.Name {
+				t.Fatalf("expected %+v but got %+v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseDedicatedHostID(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected *DedicatedHostID
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			// missing subscription
+			Input:    "/resourceGroups/mygroup1/providers/Microsoft.Compute/hostGroups/group1/hosts/host1",
+			Expected: nil,
+		},
+		{
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/hostGroups/group1",
+			Expected: nil,
+		},
+		{
+			// wrong casing
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/HostGroups/group1/Hosts/host1",
+			Expected: nil,
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/hostGroups/group1/hosts/host1",
+			Expected: &DedicatedHostID{
+				ResourceGroup: "mygroup1",
+				HostGroupName: "group1",
+				HostName:      "host1",
+			},
+		},
+		{
+			// trailing slash
+			Input:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/mygroup1/providers/Microsoft.Compute/hostGroups/group1/hosts/host1/",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			actual, err := ParseDedicatedHostID(tc.Input)
+			if tc.Expected == nil {
+				if err == nil {
+					t.Fatalf("expected an error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+			if actual.ResourceGroup != tc.Expected.ResourceGroup {
+				t.Fatalf("expected ResourceGroup %q but got %q", tc.Expected.ResourceGroup, actual.ResourceGroup)
+			}
+			if actual.HostGroupName != tc.Expected.HostGroupName {
+				t.Fatalf("expected HostGroupName %q but got %q", tc.Expected.HostGroupName, actual.HostGroupName)
+			}
+			if actual.HostName != tc.Expected.HostName {
+				t.Fatalf("expected HostName %q but got %q", tc.Expected.HostName, actual.HostName)
+			}
+		})
+	}
+}
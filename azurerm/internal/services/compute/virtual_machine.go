@@ -2,11 +2,14 @@ package compute
 
 import (
 	"fmt"
+	"log"
+	"reflect"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
 	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
 )
 
@@ -96,7 +99,8 @@ func VirtualMachineOSDiskSchema() *schema.Schema {
 						string(compute.CachingTypesNone),
 						string(compute.CachingTypesReadOnly),
 						string(compute.CachingTypesReadWrite),
-					}, false),
+					}, true),
+					DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
 				},
 				"storage_account_type": {
 					Type:     schema.TypeString,
@@ -109,7 +113,8 @@ func VirtualMachineOSDiskSchema() *schema.Schema {
 						string(compute.StorageAccountTypesPremiumLRS),
 						string(compute.StorageAccountTypesStandardLRS),
 						string(compute.StorageAccountTypesStandardSSDLRS),
-					}, false),
+					}, true),
+					DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
 				},
 
 				// Optional
@@ -126,17 +131,17 @@ func VirtualMachineOSDiskSchema() *schema.Schema {
 								ForceNew: true,
 								ValidateFunc: validation.StringInSlice([]string{
 									string(compute.Local),
-								}, false),
+								}, true),
+								DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
 							},
 						},
 					},
 				},
 
 				"disk_encryption_set_id": {
-					Type:     schema.TypeString,
-					Optional: true,
-					// TODO: make this more specific
-					ValidateFunc: azure.ValidateResourceID,
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: ValidateDiskEncryptionSetID,
 				},
 
 				"disk_size_gb": {
@@ -153,6 +158,31 @@ func VirtualMachineOSDiskSchema() *schema.Schema {
 					Computed: true,
 				},
 
+				"create_option": {
+					Type:     schema.TypeString,
+					Optional: true,
+					ForceNew: true,
+					Default:  string(compute.DiskCreateOptionTypesFromImage),
+					ValidateFunc: validation.StringInSlice([]string{
+						string(compute.DiskCreateOptionTypesFromImage),
+						string(compute.DiskCreateOptionTypesCopy),
+						string(compute.DiskCreateOptionTypesAttach),
+					}, false),
+				},
+
+				// source_resource_id is the ID of either a Snapshot or a Managed Disk to create this
+				// OS Disk from - required when create_option is `Copy` or `Attach`, and not permitted
+				// otherwise. Note that the Compute API has no separate "source" field on the embedded
+				// OS Disk for a `Copy` - both `Copy` and `Attach` reference the source via
+				// `ManagedDisk.ID`, so the wire payload for the two is identical; what differs is solely
+				// the `CreateOption` sent to the API.
+				"source_resource_id": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ForceNew:     true,
+					ValidateFunc: azure.ValidateResourceID,
+				},
+
 				"write_accelerator_enabled": {
 					Type:     schema.TypeBool,
 					Optional: true,
@@ -163,8 +193,20 @@ func VirtualMachineOSDiskSchema() *schema.Schema {
 	}
 }
 
-func ExpandVirtualMachineOSDisk(input []interface{}, osType compute.OperatingSystemTypes) *compute.OSDisk {
+func ExpandVirtualMachineOSDisk(input []interface{}, osType compute.OperatingSystemTypes) (*compute.OSDisk, error) {
 	raw := input[0].(map[string]interface{})
+
+	createOption := compute.DiskCreateOptionTypes(raw["create_option"].(string))
+	sourceResourceId := raw["source_resource_id"].(string)
+
+	requiresSource := createOption == compute.DiskCreateOptionTypesCopy || createOption == compute.DiskCreateOptionTypesAttach
+	if requiresSource && sourceResourceId == "" {
+		return nil, fmt.Errorf("`source_resource_id` must be set when `create_option` is `Copy` or `Attach`")
+	}
+	if !requiresSource && sourceResourceId != "" {
+		return nil, fmt.Errorf("`source_resource_id` can only be set when `create_option` is `Copy` or `Attach`")
+	}
+
 	disk := compute.OSDisk{
 		Caching: compute.CachingTypes(raw["caching"].(string)),
 		ManagedDisk: &compute.ManagedDiskParameters{
@@ -172,10 +214,12 @@ func ExpandVirtualMachineOSDisk(input []interface{}, osType compute.OperatingSys
 			// TODO: Disk Encryption Set ID
 		},
 		WriteAcceleratorEnabled: utils.Bool(raw["write_accelerator_enabled"].(bool)),
+		CreateOption:            createOption,
+		OsType:                  osType,
+	}
 
-		// these have to be hard-coded so there's no point exposing them
-		CreateOption: compute.DiskCreateOptionTypesFromImage,
-		OsType:       osType,
+	if sourceResourceId != "" {
+		disk.ManagedDisk.ID = utils.String(sourceResourceId)
 	}
 
 	if osDiskSize := raw["disk_size_gb"].(int); osDiskSize > 0 {
@@ -199,7 +243,7 @@ func ExpandVirtualMachineOSDisk(input []interface{}, osType compute.OperatingSys
 		disk.Name = utils.String(name)
 	}
 
-	return &disk
+	return &disk, nil
 }
 
 func FlattenVirtualMachineOSDisk(input *compute.OSDisk) []interface{} {
@@ -226,12 +270,18 @@ func FlattenVirtualMachineOSDisk(input *compute.OSDisk) []interface{} {
 
 	diskEncryptionSetId := ""
 	storageAccountType := ""
+	sourceResourceId := ""
 	if input.ManagedDisk != nil {
 		storageAccountType = string(input.ManagedDisk.StorageAccountType)
 
 		if input.ManagedDisk.DiskEncryptionSet != nil && input.ManagedDisk.DiskEncryptionSet.ID != nil {
 			diskEncryptionSetId = *input.ManagedDisk.DiskEncryptionSet.ID
 		}
+
+		requiresSource := input.CreateOption == compute.DiskCreateOptionTypesCopy || input.CreateOption == compute.DiskCreateOptionTypesAttach
+		if requiresSource && input.ManagedDisk.ID != nil {
+			sourceResourceId = *input.ManagedDisk.ID
+		}
 	}
 
 	writeAcceleratorEnabled := false
@@ -241,17 +291,32 @@ func FlattenVirtualMachineOSDisk(input *compute.OSDisk) []interface{} {
 	return []interface{}{
 		map[string]interface{}{
 			"caching":                   string(input.Caching),
+			"create_option":             string(input.CreateOption),
 			"disk_size_gb":              diskSizeGb,
 			"diff_disk_settings":        diffDiskSettings,
 			"disk_encryption_set_id":    diskEncryptionSetId,
 			"name":                      name,
+			"source_resource_id":        sourceResourceId,
 			"storage_account_type":      storageAccountType,
 			"write_accelerator_enabled": writeAcceleratorEnabled,
 		},
 	}
 }
 
-func setVirtualMachineConnectionInformation(d *schema.ResourceData, input *compute.VirtualMachineProperties) {
+// VirtualMachineConnectionInfoSchema returns the `use_private_ip` field consuming Virtual Machine
+// resources must merge into their own Schema for the opt-in read by setVirtualMachineConnectionInformation
+// to have any effect.
+func VirtualMachineConnectionInfoSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"use_private_ip": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+}
+
+func setVirtualMachineConnectionInformation(d *schema.ResourceData, input *compute.VirtualMachineProperties, meta interface{}) {
 	if input == nil {
 		return
 	}
@@ -263,10 +328,389 @@ func setVirtualMachineConnectionInformation(d *schema.ResourceData, input *compu
 		}
 	}
 
-	// TODO: determine the public ip
-	ipAddress := "1.2.3.4"
+	usePrivateIP := false
+	if v, ok := d.GetOk("use_private_ip"); ok {
+		usePrivateIP = v.(bool)
+	}
+
+	ipAddress, err := determineVirtualMachineConnectionIP(input, meta, usePrivateIP)
+	if err != nil {
+		// the VM's still usable without a connection block - so don't block the rest of the Read on this
+		log.Printf("[DEBUG] Unable to determine Virtual Machine Connection IP: %s", err)
+	}
+
 	d.SetConnInfo(map[string]string{
 		"type": provisionerType,
 		"host": ipAddress,
 	})
 }
+
+// determineVirtualMachineConnectionIP resolves the address Terraform's built-in provisioners should
+// connect to, by walking the Virtual Machine's Network Interfaces (preferring the Primary one, or the
+// first one if none is flagged as such) and resolving the Public IP attached to its Primary IP
+// Configuration. If no Public IP is attached - or the caller has opted into `use_private_ip` - the
+// Private IP Address from that IP Configuration is used instead.
+func determineVirtualMachineConnectionIP(input *compute.VirtualMachineProperties, meta interface{}, usePrivateIP bool) (string, error) {
+	if input.NetworkProfile == nil || input.NetworkProfile.NetworkInterfaces == nil {
+		return "", nil
+	}
+
+	nicID := ""
+	for _, v := range *input.NetworkProfile.NetworkInterfaces {
+		if v.ID == nil {
+			continue
+		}
+
+		if nicID == "" {
+			nicID = *v.ID
+		}
+
+		if v.NetworkInterfaceReferenceProperties != nil && v.Primary != nil && *v.Primary {
+			nicID = *v.ID
+			break
+		}
+	}
+
+	if nicID == "" {
+		return "", nil
+	}
+
+	id, err := azure.ParseAzureResourceID(nicID)
+	if err != nil {
+		return "", fmt.Errorf("parsing %q as a Network Interface ID: %+v", nicID, err)
+	}
+	nicName := id.Path["networkInterfaces"]
+	if nicName == "" {
+		return "", fmt.Errorf("determining Network Interface name from %q", nicID)
+	}
+
+	client := meta.(*clients.Client)
+	ctx := client.StopContext
+
+	nic, err := client.Network.InterfacesClient.Get(ctx, id.ResourceGroup, nicName, "")
+	if err != nil {
+		return "", fmt.Errorf("retrieving Network Interface %q (Resource Group %q): %+v", nicName, id.ResourceGroup, err)
+	}
+
+	if nic.InterfacePropertiesFormat == nil || nic.IPConfigurations == nil {
+		return "", nil
+	}
+
+	ipConfigs := *nic.IPConfigurations
+	if len(ipConfigs) == 0 {
+		return "", nil
+	}
+
+	ipConfig := ipConfigs[0]
+	for _, config := range ipConfigs {
+		if config.InterfaceIPConfigurationPropertiesFormat != nil && config.Primary != nil && *config.Primary {
+			ipConfig = config
+			break
+		}
+	}
+
+	if ipConfig.InterfaceIPConfigurationPropertiesFormat == nil {
+		return "", nil
+	}
+
+	if !usePrivateIP && ipConfig.PublicIPAddress != nil && ipConfig.PublicIPAddress.ID != nil {
+		publicIPId, err := azure.ParseAzureResourceID(*ipConfig.PublicIPAddress.ID)
+		if err != nil {
+			return "", fmt.Errorf("parsing %q as a Public IP Address ID: %+v", *ipConfig.PublicIPAddress.ID, err)
+		}
+		publicIPName := publicIPId.Path["publicIPAddresses"]
+
+		publicIP, err := client.Network.PublicIPsClient.Get(ctx, publicIPId.ResourceGroup, publicIPName, "")
+		if err != nil {
+			return "", fmt.Errorf("retrieving Public IP Address %q (Resource Group %q): %+v", publicIPName, publicIPId.ResourceGroup, err)
+		}
+
+		if publicIP.PublicIPAddressPropertiesFormat != nil && publicIP.IPAddress != nil {
+			return *publicIP.IPAddress, nil
+		}
+	}
+
+	if ipConfig.PrivateIPAddress != nil {
+		return *ipConfig.PrivateIPAddress, nil
+	}
+
+	return "", nil
+}
+
+func VirtualMachineDataDiskSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+				},
+
+				"create_option": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  string(compute.DiskCreateOptionTypesEmpty),
+					ValidateFunc: validation.StringInSlice([]string{
+						string(compute.DiskCreateOptionTypesEmpty),
+						string(compute.DiskCreateOptionTypesAttach),
+						string(compute.DiskCreateOptionTypesFromImage),
+					}, false),
+				},
+
+				"lun": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntBetween(0, 63),
+				},
+
+				"caching": {
+					Type:     schema.TypeString,
+					Required: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						string(compute.CachingTypesNone),
+						string(compute.CachingTypesReadOnly),
+						string(compute.CachingTypesReadWrite),
+					}, true),
+					DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
+				},
+
+				"disk_size_gb": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.IntBetween(0, 32767),
+				},
+
+				"storage_account_type": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						// unlike the OS Disk, Data Disks support Ultra SSDs
+						string(compute.StorageAccountTypesPremiumLRS),
+						string(compute.StorageAccountTypesStandardLRS),
+						string(compute.StorageAccountTypesStandardSSDLRS),
+						string(compute.StorageAccountTypesUltraSSDLRS),
+					}, true),
+					DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
+				},
+
+				"disk_encryption_set_id": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: ValidateDiskEncryptionSetID,
+				},
+
+				"write_accelerator_enabled": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+
+				// managed_disk_id is used to attach an existing Managed Disk rather than creating a new one
+				"managed_disk_id": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: ValidateManagedDiskID,
+				},
+			},
+		},
+	}
+}
+
+func ExpandVirtualMachineDataDisks(input []interface{}) (*[]compute.DataDisk, error) {
+	disks := make([]compute.DataDisk, 0)
+
+	for _, v := range input {
+		raw := v.(map[string]interface{})
+
+		createOption := compute.DiskCreateOptionTypes(raw["create_option"].(string))
+		managedDiskId := raw["managed_disk_id"].(string)
+
+		if createOption != compute.DiskCreateOptionTypesAttach && managedDiskId != "" {
+			return nil, fmt.Errorf("`managed_disk_id` can only be set when `create_option` is `Attach`")
+		}
+		if createOption == compute.DiskCreateOptionTypesAttach && managedDiskId == "" {
+			return nil, fmt.Errorf("`managed_disk_id` must be set when `create_option` is `Attach`")
+		}
+
+		disk := compute.DataDisk{
+			Lun:          utils.Int32(int32(raw["lun"].(int))),
+			Caching:      compute.CachingTypes(raw["caching"].(string)),
+			CreateOption: createOption,
+			ManagedDisk: &compute.ManagedDiskParameters{
+				StorageAccountType: compute.StorageAccountTypes(raw["storage_account_type"].(string)),
+			},
+			WriteAcceleratorEnabled: utils.Bool(raw["write_accelerator_enabled"].(bool)),
+		}
+
+		if name := raw["name"].(string); name != "" {
+			disk.Name = utils.String(name)
+		}
+
+		if diskSizeGb := raw["disk_size_gb"].(int); diskSizeGb > 0 {
+			disk.DiskSizeGB = utils.Int32(int32(diskSizeGb))
+		}
+
+		if id := raw["disk_encryption_set_id"].(string); id != "" {
+			disk.ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{
+				ID: utils.String(id),
+			}
+		}
+
+		if managedDiskId != "" {
+			disk.ManagedDisk.ID = utils.String(managedDiskId)
+		}
+
+		disks = append(disks, disk)
+	}
+
+	return &disks, nil
+}
+
+func FlattenVirtualMachineDataDisks(input *[]compute.DataDisk) []interface{} {
+	output := make([]interface{}, 0)
+	if input == nil {
+		return output
+	}
+
+	for _, v := range *input {
+		name := ""
+		if v.Name != nil {
+			name = *v.Name
+		}
+
+		lun := 0
+		if v.Lun != nil {
+			lun = int(*v.Lun)
+		}
+
+		diskSizeGb := 0
+		if v.DiskSizeGB != nil {
+			diskSizeGb = int(*v.DiskSizeGB)
+		}
+
+		writeAcceleratorEnabled := false
+		if v.WriteAcceleratorEnabled != nil {
+			writeAcceleratorEnabled = *v.WriteAcceleratorEnabled
+		}
+
+		storageAccountType := ""
+		diskEncryptionSetId := ""
+		managedDiskId := ""
+		if v.ManagedDisk != nil {
+			storageAccountType = string(v.ManagedDisk.StorageAccountType)
+
+			if v.ManagedDisk.DiskEncryptionSet != nil && v.ManagedDisk.DiskEncryptionSet.ID != nil {
+				diskEncryptionSetId = *v.ManagedDisk.DiskEncryptionSet.ID
+			}
+
+			if v.CreateOption == compute.DiskCreateOptionTypesAttach && v.ManagedDisk.ID != nil {
+				managedDiskId = *v.ManagedDisk.ID
+			}
+		}
+
+		output = append(output, map[string]interface{}{
+			"name":                      name,
+			"create_option":             string(v.CreateOption),
+			"lun":                       lun,
+			"caching":                   string(v.Caching),
+			"disk_size_gb":              diskSizeGb,
+			"storage_account_type":      storageAccountType,
+			"disk_encryption_set_id":    diskEncryptionSetId,
+			"write_accelerator_enabled": writeAcceleratorEnabled,
+			"managed_disk_id":           managedDiskId,
+		})
+	}
+
+	return output
+}
+
+// VirtualMachineDataDiskCustomizeDiff ensures the `lun` assigned to each `data_disk` block is unique,
+// since the Compute API will happily accept (and then reject at apply-time) duplicate LUNs. It also
+// forces a new resource when an `Empty` data disk changes, since those disks are created (and owned)
+// by the Virtual Machine itself and can't be resized/recreated in place - `Attach`ed disks are managed
+// independently so are left alone.
+func VirtualMachineDataDiskCustomizeDiff(d *schema.ResourceDiff) error {
+	disksRaw := d.Get("data_disk").([]interface{})
+
+	if err := validateVirtualMachineDataDiskLunsAreUnique(disksRaw); err != nil {
+		return err
+	}
+
+	if d.HasChange("data_disk") {
+		oldRaw, newRaw := d.GetChange("data_disk")
+		oldDisks := oldRaw.([]interface{})
+		newDisks := newRaw.([]interface{})
+
+		if virtualMachineDataDiskChangeRequiresNewResource(oldDisks, newDisks) {
+			return d.ForceNew("data_disk")
+		}
+	}
+
+	return nil
+}
+
+// validateVirtualMachineDataDiskLunsAreUnique returns an error if two or more `data_disk` blocks are
+// assigned the same `lun`, since the Compute API will happily accept (and then reject at apply-time)
+// duplicate LUNs.
+func validateVirtualMachineDataDiskLunsAreUnique(disksRaw []interface{}) error {
+	luns := make(map[int]bool)
+	for _, v := range disksRaw {
+		disk := v.(map[string]interface{})
+		lun := disk["lun"].(int)
+
+		if luns[lun] {
+			return fmt.Errorf("`lun` %d is assigned to more than one `data_disk` block - each Data Disk must have a unique `lun`", lun)
+		}
+
+		luns[lun] = true
+	}
+
+	return nil
+}
+
+// virtualMachineDataDiskChangeRequiresNewResource returns true if a non-`Attach` (`Empty` or `FromImage`) data
+// disk was added, removed or changed, since those disks are created (and owned) by the Virtual Machine
+// itself and can't be resized/recreated in place - `Attach`ed disks are managed independently so are
+// left alone.
+func virtualMachineDataDiskChangeRequiresNewResource(oldDisks, newDisks []interface{}) bool {
+	count := len(oldDisks)
+	if len(newDisks) > count {
+		count = len(newDisks)
+	}
+
+	for i := 0; i < count; i++ {
+		if i >= len(newDisks) {
+			// the disk at this index was removed entirely
+			disk := oldDisks[i].(map[string]interface{})
+			if disk["create_option"].(string) != string(compute.DiskCreateOptionTypesAttach) {
+				return true
+			}
+			continue
+		}
+
+		disk := newDisks[i].(map[string]interface{})
+		if i >= len(oldDisks) {
+			// a disk was added at this index
+			if disk["create_option"].(string) != string(compute.DiskCreateOptionTypesAttach) {
+				return true
+			}
+			continue
+		}
+
+		if reflect.DeepEqual(oldDisks[i], newDisks[i]) {
+			continue
+		}
+
+		oldDisk := oldDisks[i].(map[string]interface{})
+		if disk["create_option"].(string) != string(compute.DiskCreateOptionTypesAttach) || oldDisk["create_option"].(string) != string(compute.DiskCreateOptionTypesAttach) {
+			return true
+		}
+	}
+
+	return false
+}
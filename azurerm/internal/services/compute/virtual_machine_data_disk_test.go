@@ -0,0 +1,95 @@
+package compute
+
+import "testing"
+
+func TestExpandVirtualMachineDataDisks_attachRequiresManagedDiskId(t *testing.T) {
+	input := []interface{}{
+		map[string]interface{}{
+			"name":                      "",
+			"create_option":             "Attach",
+			"lun":                       0,
+			"caching":                   "ReadWrite",
+			"disk_size_gb":              0,
+			"storage_account_type":      "Standard_LRS",
+			"disk_encryption_set_id":    "",
+			"write_accelerator_enabled": false,
+			"managed_disk_id":           "",
+		},
+	}
+
+	if _, err := ExpandVirtualMachineDataDisks(input); err == nil {
+		t.Fatalf("expected an error when `create_option` is `Attach` without a `managed_disk_id`")
+	}
+}
+
+func TestExpandVirtualMachineDataDisks_managedDiskIdRequiresAttach(t *testing.T) {
+	input := []interface{}{
+		map[string]interface{}{
+			"name":                      "",
+			"create_option":             "Empty",
+			"lun":                       0,
+			"caching":                   "ReadWrite",
+			"disk_size_gb":              32,
+			"storage_account_type":      "Standard_LRS",
+			"disk_encryption_set_id":    "",
+			"write_accelerator_enabled": false,
+			"managed_disk_id":           "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example/providers/Microsoft.Compute/disks/example",
+		},
+	}
+
+	if _, err := ExpandVirtualMachineDataDisks(input); err == nil {
+		t.Fatalf("expected an error when `managed_disk_id` is set and `create_option` is not `Attach`")
+	}
+}
+
+func TestValidateVirtualMachineDataDiskLunsAreUnique(t *testing.T) {
+	duplicateLuns := []interface{}{
+		map[string]interface{}{"lun": 0},
+		map[string]interface{}{"lun": 1},
+		map[string]interface{}{"lun": 0},
+	}
+	if err := validateVirtualMachineDataDiskLunsAreUnique(duplicateLuns); err == nil {
+		t.Fatalf("expected an error when two `data_disk` blocks share the same `lun`")
+	}
+
+	uniqueLuns := []interface{}{
+		map[string]interface{}{"lun": 0},
+		map[string]interface{}{"lun": 1},
+	}
+	if err := validateVirtualMachineDataDiskLunsAreUnique(uniqueLuns); err != nil {
+		t.Fatalf("expected no error when every `data_disk` block has a unique `lun`, got: %+v", err)
+	}
+}
+
+func TestVirtualMachineDataDiskChangeRequiresNewResource(t *testing.T) {
+	emptyDisk := map[string]interface{}{"lun": 0, "create_option": "Empty", "disk_size_gb": 32}
+	attachedDisk := map[string]interface{}{"lun": 0, "create_option": "Attach", "managed_disk_id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example/providers/Microsoft.Compute/disks/example"}
+
+	oldDisks := []interface{}{emptyDisk}
+	changedEmptyDisk := map[string]interface{}{"lun": 0, "create_option": "Empty", "disk_size_gb": 64}
+	newDisks := []interface{}{changedEmptyDisk}
+	if !virtualMachineDataDiskChangeRequiresNewResource(oldDisks, newDisks) {
+		t.Fatalf("expected a change to an `Empty` data disk to require a new resource")
+	}
+
+	oldAttachedDisks := []interface{}{attachedDisk}
+	changedAttachedDisk := map[string]interface{}{"lun": 0, "create_option": "Attach", "managed_disk_id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example/providers/Microsoft.Compute/disks/other"}
+	newAttachedDisks := []interface{}{changedAttachedDisk}
+	if virtualMachineDataDiskChangeRequiresNewResource(oldAttachedDisks, newAttachedDisks) {
+		t.Fatalf("expected a change to an `Attach` data disk to not require a new resource")
+	}
+
+	otherEmptyDisk := map[string]interface{}{"lun": 1, "create_option": "Empty", "disk_size_gb": 32}
+	oldDisksWithRemoval := []interface{}{emptyDisk, otherEmptyDisk}
+	newDisksWithRemoval := []interface{}{emptyDisk}
+	if !virtualMachineDataDiskChangeRequiresNewResource(oldDisksWithRemoval, newDisksWithRemoval) {
+		t.Fatalf("expected removing an `Empty` data disk to require a new resource")
+	}
+
+	otherAttachedDisk := map[string]interface{}{"lun": 1, "create_option": "Attach", "managed_disk_id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example/providers/Microsoft.Compute/disks/other"}
+	oldAttachedDisksWithRemoval := []interface{}{attachedDisk, otherAttachedDisk}
+	newAttachedDisksWithRemoval := []interface{}{attachedDisk}
+	if virtualMachineDataDiskChangeRequiresNewResource(oldAttachedDisksWithRemoval, newAttachedDisksWithRemoval) {
+		t.Fatalf("expected removing an `Attach` data disk to not require a new resource")
+	}
+}
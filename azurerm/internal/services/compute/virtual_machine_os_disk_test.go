@@ -0,0 +1,45 @@
+package compute
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+)
+
+func TestExpandVirtualMachineOSDisk_copyOrAttachRequiresSourceResourceId(t *testing.T) {
+	input := []interface{}{
+		map[string]interface{}{
+			"caching":                   "ReadWrite",
+			"create_option":             "Attach",
+			"source_resource_id":        "",
+			"disk_encryption_set_id":    "",
+			"disk_size_gb":              0,
+			"name":                      "",
+			"storage_account_type":      "Standard_LRS",
+			"write_accelerator_enabled": false,
+		},
+	}
+
+	if _, err := ExpandVirtualMachineOSDisk(input, compute.Linux); err == nil {
+		t.Fatalf("expected an error when `create_option` is `Attach` without a `source_resource_id`")
+	}
+}
+
+func TestExpandVirtualMachineOSDisk_sourceResourceIdRequiresCopyOrAttach(t *testing.T) {
+	input := []interface{}{
+		map[string]interface{}{
+			"caching":                   "ReadWrite",
+			"create_option":             "FromImage",
+			"source_resource_id":        "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example/providers/Microsoft.Compute/snapshots/example",
+			"disk_encryption_set_id":    "",
+			"disk_size_gb":              0,
+			"name":                      "",
+			"storage_account_type":      "Standard_LRS",
+			"write_accelerator_enabled": false,
+		},
+	}
+
+	if _, err := ExpandVirtualMachineOSDisk(input, compute.Linux); err == nil {
+		t.Fatalf("expected an error when `source_resource_id` is set and `create_option` is `FromImage`")
+	}
+}
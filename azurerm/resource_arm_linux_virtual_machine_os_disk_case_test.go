@@ -0,0 +1,103 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/tf"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/acceptance"
+)
+
+// TestAccAzureRMLinuxVirtualMachine_osDiskCaseInsensitive confirms that re-applying the same
+// configuration with different casing on the `caching` / `storage_account_type` enum fields of the
+// `os_disk` block produces no plan diff - the Compute API treats these enums case-insensitively, and a
+// perpetual diff here would otherwise surface every time another tool (or the API itself) echoes back a
+// different casing than the one the user wrote.
+func TestAccAzureRMLinuxVirtualMachine_osDiskCaseInsensitive(t *testing.T) {
+	resourceName := "azurerm_linux_virtual_machine.test"
+	ri := tf.AccRandTimeInt()
+	location := acceptance.Location()
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { acceptance.PreCheck(t) },
+		Providers:    acceptance.SupportedProviders,
+		CheckDestroy: testCheckAzureRMLinuxVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMLinuxVirtualMachine_osDiskCaseInsensitive(ri, location, "ReadWrite", "Standard_LRS"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMLinuxVirtualMachineExists(resourceName),
+				),
+			},
+			{
+				Config:             testAccAzureRMLinuxVirtualMachine_osDiskCaseInsensitive(ri, location, "readwrite", "standard_lrs"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func testAccAzureRMLinuxVirtualMachine_osDiskCaseInsensitive(rInt int, location, caching, storageAccountType string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_virtual_network" "test" {
+  name                = "acctestvn-%d"
+  address_space       = ["10.0.0.0/16"]
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+}
+
+resource "azurerm_subnet" "test" {
+  name                 = "internal"
+  resource_group_name  = azurerm_resource_group.test.name
+  virtual_network_name = azurerm_virtual_network.test.name
+  address_prefixes     = ["10.0.2.0/24"]
+}
+
+resource "azurerm_network_interface" "test" {
+  name                = "acctestnic-%d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  ip_configuration {
+    name                          = "internal"
+    subnet_id                     = azurerm_subnet.test.id
+    private_ip_address_allocation = "Dynamic"
+  }
+}
+
+resource "azurerm_linux_virtual_machine" "test" {
+  name                = "acctestvm-%d"
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+  size                = "Standard_F2"
+  admin_username      = "adminuser"
+  network_interface_ids = [
+    azurerm_network_interface.test.id,
+  ]
+
+  admin_ssh_key {
+    username   = "adminuser"
+    public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCqaZoyiz1qbdOQ8xEf6uEu1cCwYowi/J8K2u1RCX0jr8CXpvxbSmV9AJCgf1nrT7D6VJ6DvJztr5n6gG9Lj+s1u4CjnaPMT+L82JbxUjZvkAGgQzAkOYFO0fI5zB7q19Xwv9p8PGlf5SgFKEhjUzyN6Xaxd+dOhMVeKhWBeCsLG7OqrTTcDTsJ0PsnK+lqi5EPACQnPw0RQIP9kkC33PEWVkeplmFq3FFO5VsADTXmvUuLdfgSwHQHaTOuhgv5UMHzGOYS8dWQQSkmKOkCapTEPZZ0wZ+JpvZ" # fake key for testing
+  }
+
+  os_disk {
+    caching              = "%s"
+    storage_account_type = "%s"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "UbuntuServer"
+    sku       = "16.04-LTS"
+    version   = "latest"
+  }
+}
+`, rInt, location, rInt, rInt, rInt, caching, storageAccountType)
+}
@@ -0,0 +1,582 @@
+package azurerm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/recoveryservices/mgmt/2018-07-10/siterecovery"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/tf"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/services/compute"
+	azSchema "github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/tf/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/timeouts"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+type siteRecoveryReplicatedVmId struct {
+	ResourceGroup           string
+	VaultName               string
+	FabricName              string
+	ProtectionContainerName string
+	Name                    string
+}
+
+func parseSiteRecoveryReplicatedVmID(input string) (*siteRecoveryReplicatedVmId, error) {
+	id, err := azure.ParseAzureResourceID(input)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to parse Site Recovery Replicated VM ID %q: %+v", input, err)
+	}
+
+	replicatedVm := siteRecoveryReplicatedVmId{
+		ResourceGroup: id.ResourceGroup,
+	}
+
+	if replicatedVm.VaultName, err = id.PopSegment("vaults"); err != nil {
+		return nil, err
+	}
+
+	if replicatedVm.FabricName, err = id.PopSegment("replicationFabrics"); err != nil {
+		return nil, err
+	}
+
+	if replicatedVm.ProtectionContainerName, err = id.PopSegment("replicationProtectionContainers"); err != nil {
+		return nil, err
+	}
+
+	if replicatedVm.Name, err = id.PopSegment("replicationProtectedItems"); err != nil {
+		return nil, err
+	}
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &replicatedVm, nil
+}
+
+func resourceArmSiteRecoveryReplicatedVm() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmSiteRecoveryReplicatedVmCreate,
+		Read:   resourceArmSiteRecoveryReplicatedVmRead,
+		Update: resourceArmSiteRecoveryReplicatedVmUpdate,
+		Delete: resourceArmSiteRecoveryReplicatedVmDelete,
+
+		Importer: azSchema.ValidateResourceIDPriorToImport(func(id string) error {
+			_, err := parseSiteRecoveryReplicatedVmID(id)
+			return err
+		}),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(180 * time.Minute),
+			Update: schema.DefaultTimeout(80 * time.Minute),
+			Delete: schema.DefaultTimeout(80 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+
+			"resource_group_name": azure.SchemaResourceGroupName(),
+
+			"recovery_vault_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+
+			"source_recovery_fabric_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+
+			"source_recovery_protection_container_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+
+			"target_recovery_fabric_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			"target_recovery_protection_container_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			"recovery_replication_policy_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			"source_vm_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: compute.ValidateVirtualMachineID,
+			},
+
+			"target_resource_group_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			// the Azure Site Recovery API has no way to move an already-protected VM onto a different
+			// recovery network after `VMNics`/`RecoveryAzureNetworkID` are set on enable-protection, so
+			// changing this forces a new resource rather than silently leaving the real network untouched
+			"target_network_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			"network_interface": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source_network_interface_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: azure.ValidateResourceID,
+						},
+						"target_subnet_name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"recovery_public_ip_address_id": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: azure.ValidateResourceID,
+						},
+					},
+				},
+			},
+
+			"managed_disk": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"disk_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"staging_storage_account_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: azure.ValidateResourceID,
+						},
+						"target_resource_group_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: azure.ValidateResourceID,
+						},
+						"target_disk_encryption_set_id": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: azure.ValidateResourceID,
+						},
+						"target_storage_account_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								string(siterecovery.Standard),
+								string(siterecovery.PremiumLRS),
+								string(siterecovery.StandardSSDLRS),
+							}, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceArmSiteRecoveryReplicatedVmCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).RecoveryServices.ReplicationProtectedItemsClient(d.Get("resource_group_name").(string), d.Get("recovery_vault_name").(string))
+	ctx, cancel := timeouts.ForCreate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	vaultName := d.Get("recovery_vault_name").(string)
+	fabricName := d.Get("source_recovery_fabric_name").(string)
+	protectionContainerName := d.Get("source_recovery_protection_container_name").(string)
+
+	existing, err := client.Get(ctx, fabricName, protectionContainerName, name)
+	if err != nil {
+		if !utils.ResponseWasNotFound(existing.Response) {
+			return fmt.Errorf("checking for presence of existing Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+		}
+	}
+	if existing.ID != nil && *existing.ID != "" {
+		return tf.ImportAsExistsError("azurerm_site_recovery_replicated_vm", *existing.ID)
+	}
+
+	vmID := d.Get("source_vm_id").(string)
+	disksRaw := d.Get("managed_disk").([]interface{})
+	nicsRaw := d.Get("network_interface").([]interface{})
+
+	parameters := siterecovery.EnableProtectionInput{
+		Properties: &siterecovery.EnableProtectionInputProperties{
+			PolicyID: utils.String(d.Get("recovery_replication_policy_id").(string)),
+			ProviderSpecificDetails: siterecovery.A2AEnableProtectionInput{
+				FabricObjectID:           utils.String(vmID),
+				RecoveryContainerID:      utils.String(d.Get("target_recovery_protection_container_id").(string)),
+				RecoveryResourceGroupID:  utils.String(d.Get("target_resource_group_id").(string)),
+				RecoveryAvailabilityType: siterecovery.Single,
+				VMManagedDisks:           expandSiteRecoveryReplicatedVmManagedDisks(disksRaw),
+				MultiVMGroupName:         utils.String(name),
+				RecoveryAzureNetworkID:   utils.String(d.Get("target_network_id").(string)),
+				VMNics:                   expandSiteRecoveryReplicatedVmNics(nicsRaw),
+			},
+		},
+	}
+
+	future, err := client.Create(ctx, fabricName, protectionContainerName, name, parameters)
+	if err != nil {
+		return fmt.Errorf("creating Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+	}
+
+	if err := future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return fmt.Errorf("waiting for creation of Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+	}
+
+	log.Printf("[DEBUG] Waiting for Site Recovery Replicated VM %q (Vault %q) to report a healthy replication state", name, vaultName)
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"NotStarted", "InProgress", "Warning"},
+		Target:     []string{"Normal"},
+		Refresh:    siteRecoveryReplicatedVmHealthRefreshFunc(ctx, client, fabricName, protectionContainerName, name),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		MinTimeout: 30 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("waiting for Site Recovery Replicated VM %q (Vault %q) to become healthy: %+v", name, vaultName, err)
+	}
+
+	resp, err := client.Get(ctx, fabricName, protectionContainerName, name)
+	if err != nil {
+		return fmt.Errorf("retrieving Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	return resourceArmSiteRecoveryReplicatedVmRead(d, meta)
+}
+
+func resourceArmSiteRecoveryReplicatedVmUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).RecoveryServices.ReplicationProtectedItemsClient(d.Get("resource_group_name").(string), d.Get("recovery_vault_name").(string))
+	ctx, cancel := timeouts.ForUpdate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	vaultName := d.Get("recovery_vault_name").(string)
+	fabricName := d.Get("source_recovery_fabric_name").(string)
+	protectionContainerName := d.Get("source_recovery_protection_container_name").(string)
+
+	disksRaw := d.Get("managed_disk").([]interface{})
+	nicsRaw := d.Get("network_interface").([]interface{})
+
+	parameters := siterecovery.UpdateReplicationProtectedItemInput{
+		Properties: &siterecovery.UpdateReplicationProtectedItemInputProperties{
+			RecoveryAzureVMName: utils.String(name),
+			ProviderSpecificDetails: siterecovery.A2AUpdateReplicationProtectedItemInput{
+				ManagedDiskUpdateDetails: expandSiteRecoveryReplicatedVmManagedDiskUpdates(disksRaw),
+			},
+			VMNics: expandSiteRecoveryReplicatedVmNicUpdates(nicsRaw),
+		},
+	}
+
+	future, err := client.Update(ctx, fabricName, protectionContainerName, name, parameters)
+	if err != nil {
+		return fmt.Errorf("updating Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+	}
+
+	if err := future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return fmt.Errorf("waiting for update of Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+	}
+
+	return resourceArmSiteRecoveryReplicatedVmRead(d, meta)
+}
+
+func resourceArmSiteRecoveryReplicatedVmRead(d *schema.ResourceData, meta interface{}) error {
+	id, err := parseSiteRecoveryReplicatedVmID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*clients.Client).RecoveryServices.ReplicationProtectedItemsClient(id.ResourceGroup, id.VaultName)
+	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	resp, err := client.Get(ctx, id.FabricName, id.ProtectionContainerName, id.Name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			log.Printf("[DEBUG] Site Recovery Replicated VM %q was not found - removing from state", id.Name)
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("reading Site Recovery Replicated VM %q: %+v", id.Name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", id.ResourceGroup)
+	d.Set("recovery_vault_name", id.VaultName)
+	d.Set("source_recovery_fabric_name", id.FabricName)
+	d.Set("source_recovery_protection_container_name", id.ProtectionContainerName)
+
+	if props := resp.Properties; props != nil {
+		d.Set("recovery_replication_policy_id", props.PolicyID)
+
+		if details, ok := props.ProviderSpecificDetails.AsA2AReplicationDetails(); ok {
+			d.Set("source_vm_id", details.FabricObjectID)
+			d.Set("target_recovery_fabric_id", details.RecoveryFabricID)
+			d.Set("target_recovery_protection_container_id", details.RecoveryContainerID)
+			d.Set("target_resource_group_id", details.RecoveryAzureResourceGroupID)
+			d.Set("target_network_id", details.RecoveryAzureNetworkID)
+
+			if err := d.Set("managed_disk", flattenSiteRecoveryReplicatedVmManagedDisks(details.ProtectedManagedDisks)); err != nil {
+				return fmt.Errorf("setting `managed_disk`: %+v", err)
+			}
+
+			if err := d.Set("network_interface", flattenSiteRecoveryReplicatedVmNics(details.VMNics)); err != nil {
+				return fmt.Errorf("setting `network_interface`: %+v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceArmSiteRecoveryReplicatedVmDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).RecoveryServices.ReplicationProtectedItemsClient(d.Get("resource_group_name").(string), d.Get("recovery_vault_name").(string))
+	ctx, cancel := timeouts.ForDelete(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	vaultName := d.Get("recovery_vault_name").(string)
+	fabricName := d.Get("source_recovery_fabric_name").(string)
+	protectionContainerName := d.Get("source_recovery_protection_container_name").(string)
+
+	future, err := client.Delete(ctx, fabricName, protectionContainerName, name, siterecovery.DisableProtectionInput{})
+	if err != nil {
+		return fmt.Errorf("disabling replication for Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+	}
+
+	if err := future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return fmt.Errorf("waiting for deletion of Site Recovery Replicated VM %q (Vault %q): %+v", name, vaultName, err)
+	}
+
+	return nil
+}
+
+func siteRecoveryReplicatedVmHealthRefreshFunc(ctx context.Context, client *siterecovery.ReplicationProtectedItemsClient, fabricName, protectionContainerName, name string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := client.Get(ctx, fabricName, protectionContainerName, name)
+		if err != nil {
+			return nil, "", fmt.Errorf("polling replication health for Site Recovery Replicated VM %q: %+v", name, err)
+		}
+
+		if props := resp.Properties; props != nil && props.ReplicationHealth != nil {
+			return resp, *props.ReplicationHealth, nil
+		}
+
+		return resp, "InProgress", nil
+	}
+}
+
+func expandSiteRecoveryReplicatedVmManagedDisks(input []interface{}) *[]siterecovery.A2AVMManagedDiskInputDetails {
+	disks := make([]siterecovery.A2AVMManagedDiskInputDetails, 0)
+
+	for _, raw := range input {
+		disk := raw.(map[string]interface{})
+
+		details := siterecovery.A2AVMManagedDiskInputDetails{
+			DiskID:                              utils.String(disk["disk_id"].(string)),
+			RecoveryResourceGroupID:             utils.String(disk["target_resource_group_id"].(string)),
+			PrimaryStagingAzureStorageAccountID: utils.String(disk["staging_storage_account_id"].(string)),
+		}
+
+		if v := disk["target_storage_account_type"].(string); v != "" {
+			details.RecoveryReplicaDiskAccountType = utils.String(v)
+			details.RecoveryTargetDiskAccountType = utils.String(v)
+		}
+
+		if v := disk["target_disk_encryption_set_id"].(string); v != "" {
+			details.RecoveryDiskEncryptionSetID = utils.String(v)
+		}
+
+		disks = append(disks, details)
+	}
+
+	return &disks
+}
+
+func expandSiteRecoveryReplicatedVmManagedDiskUpdates(input []interface{}) *[]siterecovery.A2AVMManagedDiskUpdateDetails {
+	disks := make([]siterecovery.A2AVMManagedDiskUpdateDetails, 0)
+
+	for _, raw := range input {
+		disk := raw.(map[string]interface{})
+
+		details := siterecovery.A2AVMManagedDiskUpdateDetails{
+			DiskID: utils.String(disk["disk_id"].(string)),
+		}
+
+		if v := disk["target_storage_account_type"].(string); v != "" {
+			details.RecoveryTargetDiskAccountType = utils.String(v)
+		}
+
+		if v := disk["target_disk_encryption_set_id"].(string); v != "" {
+			details.DiskEncryptionSetID = utils.String(v)
+		}
+
+		disks = append(disks, details)
+	}
+
+	return &disks
+}
+
+func expandSiteRecoveryReplicatedVmNics(input []interface{}) *[]siterecovery.VMNicInputDetails {
+	nics := make([]siterecovery.VMNicInputDetails, 0)
+
+	for _, raw := range input {
+		nic := raw.(map[string]interface{})
+
+		details := siterecovery.VMNicInputDetails{
+			NicID:                 utils.String(nic["source_network_interface_id"].(string)),
+			RecoveryNicSubnetName: utils.String(nic["target_subnet_name"].(string)),
+		}
+
+		if v := nic["recovery_public_ip_address_id"].(string); v != "" {
+			details.RecoveryPublicIPAddressID = utils.String(v)
+		}
+
+		nics = append(nics, details)
+	}
+
+	return &nics
+}
+
+func expandSiteRecoveryReplicatedVmNicUpdates(input []interface{}) *[]siterecovery.VMNicDetails {
+	nics := make([]siterecovery.VMNicDetails, 0)
+
+	for _, raw := range input {
+		nic := raw.(map[string]interface{})
+
+		details := siterecovery.VMNicDetails{
+			NicID:                 utils.String(nic["source_network_interface_id"].(string)),
+			RecoveryNicSubnetName: utils.String(nic["target_subnet_name"].(string)),
+		}
+
+		if v := nic["recovery_public_ip_address_id"].(string); v != "" {
+			details.RecoveryPublicIPAddressID = utils.String(v)
+		}
+
+		nics = append(nics, details)
+	}
+
+	return &nics
+}
+
+func flattenSiteRecoveryReplicatedVmNics(input *[]siterecovery.VMNicDetails) []interface{} {
+	output := make([]interface{}, 0)
+	if input == nil {
+		return output
+	}
+
+	for _, nic := range *input {
+		sourceNetworkInterfaceID := ""
+		if nic.NicID != nil {
+			sourceNetworkInterfaceID = *nic.NicID
+		}
+
+		targetSubnetName := ""
+		if nic.RecoveryNicSubnetName != nil {
+			targetSubnetName = *nic.RecoveryNicSubnetName
+		}
+
+		recoveryPublicIPAddressID := ""
+		if nic.RecoveryPublicIPAddressID != nil {
+			recoveryPublicIPAddressID = *nic.RecoveryPublicIPAddressID
+		}
+
+		output = append(output, map[string]interface{}{
+			"source_network_interface_id":   sourceNetworkInterfaceID,
+			"target_subnet_name":            targetSubnetName,
+			"recovery_public_ip_address_id": recoveryPublicIPAddressID,
+		})
+	}
+
+	return output
+}
+
+func flattenSiteRecoveryReplicatedVmManagedDisks(input *[]siterecovery.A2AProtectedManagedDiskDetails) []interface{} {
+	output := make([]interface{}, 0)
+	if input == nil {
+		return output
+	}
+
+	for _, disk := range *input {
+		diskID := ""
+		if disk.DiskID != nil {
+			diskID = *disk.DiskID
+		}
+
+		stagingStorageAccountID := ""
+		if disk.PrimaryStagingAzureStorageAccountID != nil {
+			stagingStorageAccountID = *disk.PrimaryStagingAzureStorageAccountID
+		}
+
+		targetResourceGroupID := ""
+		if disk.RecoveryResourceGroupID != nil {
+			targetResourceGroupID = *disk.RecoveryResourceGroupID
+		}
+
+		targetDiskEncryptionSetID := ""
+		if disk.RecoveryDiskEncryptionSetID != nil {
+			targetDiskEncryptionSetID = *disk.RecoveryDiskEncryptionSetID
+		}
+
+		targetStorageAccountType := ""
+		if disk.RecoveryTargetDiskAccountType != nil {
+			targetStorageAccountType = *disk.RecoveryTargetDiskAccountType
+		}
+
+		output = append(output, map[string]interface{}{
+			"disk_id":                       diskID,
+			"staging_storage_account_id":    stagingStorageAccountID,
+			"target_resource_group_id":      targetResourceGroupID,
+			"target_disk_encryption_set_id": targetDiskEncryptionSetID,
+			"target_storage_account_type":   targetStorageAccountType,
+		})
+	}
+
+	return output
+}
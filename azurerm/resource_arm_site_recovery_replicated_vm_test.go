@@ -0,0 +1,131 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/tf"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/acceptance"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
+)
+
+func TestAccAzureRMSiteRecoveryReplicatedVm_basic(t *testing.T) {
+	resourceGroupName := "azurerm_resource_group.test"
+	vaultName := "azurerm_recovery_services_vault.test"
+	resourceName := "azurerm_site_recovery_replicated_vm.test"
+	ri := tf.AccRandTimeInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { acceptance.PreCheck(t) },
+		Providers:    acceptance.SupportedProviders,
+		CheckDestroy: testCheckAzureRMResourceGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMSiteRecoveryReplicatedVm_basic(ri, acceptance.Location(), acceptance.AltLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMSiteRecoveryReplicatedVmExists(resourceGroupName, vaultName, resourceName),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccAzureRMSiteRecoveryReplicatedVm_basic(rInt int, location string, altLocation string) string {
+	fabricAndPolicy := fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_recovery_services_vault" "test" {
+  name                = "acctest-vault-%d"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  sku                 = "Standard"
+}
+
+resource "azurerm_site_recovery_fabric" "source" {
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  recovery_vault_name = "${azurerm_recovery_services_vault.test.name}"
+  name                = "acctest-fabric-source-%d"
+  location            = "${azurerm_resource_group.test.location}"
+}
+
+resource "azurerm_site_recovery_fabric" "target" {
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  recovery_vault_name = "${azurerm_recovery_services_vault.test.name}"
+  name                = "acctest-fabric-target-%d"
+  location            = "%s"
+}
+
+resource "azurerm_site_recovery_replication_policy" "test" {
+  resource_group_name                                  = "${azurerm_resource_group.test.name}"
+  recovery_vault_name                                  = "${azurerm_recovery_services_vault.test.name}"
+  name                                                 = "acctest-policy-%d"
+  recovery_point_retention_in_minutes                  = "${24 * 60}"
+  application_consistent_snapshot_frequency_in_minutes = "${4 * 60}"
+}
+`, rInt, location, rInt, rInt, rInt, altLocation, rInt)
+
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_site_recovery_replicated_vm" "test" {
+  name                                       = "acctest-replicated-vm-%d"
+  resource_group_name                        = "${azurerm_resource_group.test.name}"
+  recovery_vault_name                        = "${azurerm_recovery_services_vault.test.name}"
+  source_recovery_fabric_name                = "${azurerm_site_recovery_fabric.source.name}"
+  target_recovery_fabric_id                  = "${azurerm_site_recovery_fabric.target.id}"
+  source_recovery_protection_container_name  = "acctest-container-source-%d"
+  target_recovery_protection_container_id    = "${azurerm_site_recovery_fabric.target.id}/replicationProtectionContainers/acctest-container-target-%d"
+  recovery_replication_policy_id             = "${azurerm_site_recovery_replication_policy.test.id}"
+  source_vm_id                               = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG-%d/providers/Microsoft.Compute/virtualMachines/acctest-vm-%d"
+  target_resource_group_id                   = "${azurerm_resource_group.test.id}"
+}
+`, fabricAndPolicy, rInt, rInt, rInt, rInt, rInt)
+}
+
+func testCheckAzureRMSiteRecoveryReplicatedVmExists(resourceGroupStateName, vaultStateName string, resourceStateName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceGroupState, ok := s.RootModule().Resources[resourceGroupStateName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceGroupStateName)
+		}
+		vaultState, ok := s.RootModule().Resources[vaultStateName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", vaultStateName)
+		}
+		vmState, ok := s.RootModule().Resources[resourceStateName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceStateName)
+		}
+
+		resourceGroupName := resourceGroupState.Primary.Attributes["name"]
+		vaultName := vaultState.Primary.Attributes["name"]
+		fabricName := vmState.Primary.Attributes["source_recovery_fabric_name"]
+		protectionContainerName := vmState.Primary.Attributes["source_recovery_protection_container_name"]
+		name := vmState.Primary.Attributes["name"]
+
+		client := acceptance.AzureProvider.Meta().(*clients.Client).RecoveryServices.ReplicationProtectedItemsClient(resourceGroupName, vaultName)
+		ctx := acceptance.AzureProvider.Meta().(*clients.Client).StopContext
+
+		resp, err := client.Get(ctx, fabricName, protectionContainerName, name)
+		if err != nil {
+			return fmt.Errorf("Bad: Get on ReplicationProtectedItemsClient: %+v", err)
+		}
+
+		if resp.Response.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("Bad: replicated vm: %q does not exist", name)
+		}
+
+		return nil
+	}
+}